@@ -0,0 +1,120 @@
+package dns
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	log "github.com/sirupsen/logrus"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+)
+
+// queryLogPruneInterval is how often each query-logging-enabled group's log is pruned down to
+// its configured retention window
+const queryLogPruneInterval = 24 * time.Hour
+
+// Forwarder resolves queries for the peer's configured NameServerGroups by running each through
+// the shared resolution chain (query logging -> blocking -> caching -> upstream), and keeps
+// each group's blocklists refreshed and query log pruned in the background for as long as the
+// group is active.
+type Forwarder struct {
+	chain            nbdns.Resolver
+	blockingResolver *nbdns.BlockingResolver
+	cachingResolver  *nbdns.CachingResolver
+	queryLogStore    nbdns.QueryLogStore
+
+	mu        sync.Mutex
+	cancelers map[string]context.CancelFunc // per NameServerGroup.ID, stops its background tasks
+}
+
+// NewForwarder builds the resolution chain and returns a Forwarder ready to serve queries.
+// queryLogStore may be nil if no group has query logging enabled.
+func NewForwarder(queryLogStore nbdns.QueryLogStore) *Forwarder {
+	upstream := NewGroupResolver()
+	caching := nbdns.NewCachingResolver(upstream)
+	blocking := nbdns.NewBlockingResolver(caching)
+	queryLogging := nbdns.NewQueryLoggingResolver(blocking, queryLogStore)
+
+	return &Forwarder{
+		chain:            queryLogging,
+		blockingResolver: blocking,
+		cachingResolver:  caching,
+		queryLogStore:    queryLogStore,
+		cancelers:        make(map[string]context.CancelFunc),
+	}
+}
+
+// Resolve runs msg through the resolution chain for group. Called by the peer's local DNS
+// server for every query matched to group.
+func (f *Forwarder) Resolve(ctx context.Context, group *nbdns.NameServerGroup, msg *dns.Msg) (*dns.Msg, error) {
+	return f.chain.Resolve(ctx, group, msg)
+}
+
+// FlushCache discards group's cached responses, e.g. when the operator hits the
+// DELETE .../cache endpoint
+func (f *Forwarder) FlushCache(groupID string) {
+	f.cachingResolver.Flush(groupID)
+}
+
+// UpdateGroup (re)starts group's background blocklist refresher and query-log pruner, stopping
+// any previous ones for the same group. Called whenever the management server pushes a new or
+// changed NameServerGroup.
+func (f *Forwarder) UpdateGroup(group *nbdns.NameServerGroup, blocklistStore nbdns.BlocklistCacheStore) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if cancel, ok := f.cancelers[group.ID]; ok {
+		cancel()
+		delete(f.cancelers, group.ID)
+	}
+
+	if len(group.Blocklists) == 0 {
+		f.blockingResolver.UpdateBlockSet(group.ID, nil)
+	}
+
+	if len(group.Blocklists) == 0 && !group.QueryLogEnabled {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancelers[group.ID] = cancel
+
+	if len(group.Blocklists) > 0 {
+		refresher := nbdns.NewBlocklistRefresher(f.blockingResolver, blocklistStore)
+		go refresher.Run(ctx, group)
+	}
+
+	if group.QueryLogEnabled && f.queryLogStore != nil {
+		go f.pruneQueryLogLoop(ctx, group)
+	}
+}
+
+func (f *Forwarder) pruneQueryLogLoop(ctx context.Context, group *nbdns.NameServerGroup) {
+	ticker := time.NewTicker(queryLogPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := nbdns.PruneQueryLogs(ctx, f.queryLogStore, group); err != nil {
+				log.Warnf("failed to prune query log for nameserver group %s: %s", group.ID, err)
+			}
+		}
+	}
+}
+
+// RemoveGroup stops refreshing group's blocklists and pruning its query log, e.g. after it's
+// deleted or disabled
+func (f *Forwarder) RemoveGroup(groupID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if cancel, ok := f.cancelers[groupID]; ok {
+		cancel()
+		delete(f.cancelers, groupID)
+	}
+}
@@ -0,0 +1,44 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/miekg/dns"
+	log "github.com/sirupsen/logrus"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+)
+
+// GroupResolver is the innermost link in a NameServerGroup's resolution chain: it dispatches a
+// query to the group's configured upstreams, trying each in turn (UDP, TCP, DoT, or DoH,
+// depending on each NameServer's NSType) until one answers. It satisfies nbdns.Resolver so it
+// can sit behind the shared blocking/caching/query-logging chain.
+type GroupResolver struct {
+	resolver *upstreamResolver
+}
+
+// NewGroupResolver creates a GroupResolver
+func NewGroupResolver() *GroupResolver {
+	return &GroupResolver{resolver: newUpstreamResolver()}
+}
+
+// Resolve tries each nameserver in group in order, returning the first successful response
+func (g *GroupResolver) Resolve(ctx context.Context, group *nbdns.NameServerGroup, msg *dns.Msg) (*dns.Msg, error) {
+	var lastErr error
+	for _, ns := range group.NameServers {
+		resp, err := g.resolver.exchange(ctx, ns, msg)
+		if err != nil {
+			log.Debugf("nameserver %s failed for group %s: %s", ns, group.ID, err)
+			lastErr = err
+			continue
+		}
+		nbdns.RecordUpstream(ctx, ns.String())
+		return resp, nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("nameserver group %s has no nameservers configured", group.ID)
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,134 @@
+// Package dns implements the client-side resolution of queries against the nameservers
+// configured on a NameServerGroup, dialing whichever transport each nbdns.NameServer requires.
+package dns
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/miekg/dns"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+)
+
+// upstreamTimeout bounds a single exchange with an upstream nameserver, across every transport
+const upstreamTimeout = 15 * time.Second
+
+// upstreamResolver dispatches a query to a single nbdns.NameServer over the transport implied
+// by its NSType: plain UDP/TCP, DNS-over-TLS (RFC 7858), or DNS-over-HTTPS (RFC 8484).
+type upstreamResolver struct {
+	httpClient *http.Client
+}
+
+func newUpstreamResolver() *upstreamResolver {
+	return &upstreamResolver{
+		httpClient: &http.Client{Timeout: upstreamTimeout},
+	}
+}
+
+// exchange sends msg to ns and returns its response
+func (u *upstreamResolver) exchange(ctx context.Context, ns nbdns.NameServer, msg *dns.Msg) (*dns.Msg, error) {
+	switch ns.NSType {
+	case nbdns.UDPNameServerType:
+		return u.exchangeClassic(ctx, "udp", ns, msg)
+	case nbdns.TCPNameServerType:
+		return u.exchangeClassic(ctx, "tcp", ns, msg)
+	case nbdns.DoTNameServerType:
+		return u.exchangeDoT(ctx, ns, msg)
+	case nbdns.DoHNameServerType:
+		return u.exchangeDoH(ctx, ns, msg)
+	default:
+		return nil, fmt.Errorf("unsupported nameserver type %s", ns.NSType)
+	}
+}
+
+func (u *upstreamResolver) exchangeClassic(ctx context.Context, network string, ns nbdns.NameServer, msg *dns.Msg) (*dns.Msg, error) {
+	client := &dns.Client{Net: network, Timeout: upstreamTimeout}
+	resp, _, err := client.ExchangeContext(ctx, msg, net.JoinHostPort(ns.Host(), strconv.Itoa(ns.Port)))
+	if err != nil {
+		return nil, fmt.Errorf("exchange with %s nameserver %s: %w", network, ns, err)
+	}
+	return resp, nil
+}
+
+func (u *upstreamResolver) exchangeDoT(ctx context.Context, ns nbdns.NameServer, msg *dns.Msg) (*dns.Msg, error) {
+	deadline := time.Now().Add(upstreamTimeout)
+	dialer := tls.Dialer{
+		NetDialer: &net.Dialer{Deadline: deadline},
+		Config:    &tls.Config{ServerName: dotServerName(ns)},
+	}
+
+	addr := net.JoinHostPort(ns.Host(), strconv.Itoa(ns.Port))
+	netConn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial DoT nameserver %s: %w", ns, err)
+	}
+	defer netConn.Close()
+
+	if err := netConn.SetDeadline(deadline); err != nil {
+		return nil, fmt.Errorf("set deadline for DoT nameserver %s: %w", ns, err)
+	}
+
+	conn := &dns.Conn{Conn: netConn}
+	if err := conn.WriteMsg(msg); err != nil {
+		return nil, fmt.Errorf("write query to DoT nameserver %s: %w", ns, err)
+	}
+
+	resp, err := conn.ReadMsg()
+	if err != nil {
+		return nil, fmt.Errorf("read response from DoT nameserver %s: %w", ns, err)
+	}
+	return resp, nil
+}
+
+// dotServerName returns the hostname DoT validates the upstream's certificate against,
+// preferring the operator-configured Hostname/SNI override over the dial target
+func dotServerName(ns nbdns.NameServer) string {
+	if ns.Hostname != "" {
+		return ns.Hostname
+	}
+	return ns.Host()
+}
+
+func (u *upstreamResolver) exchangeDoH(ctx context.Context, ns nbdns.NameServer, msg *dns.Msg) (*dns.Msg, error) {
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("pack query for DoH nameserver %s: %w", ns, err)
+	}
+
+	url := fmt.Sprintf("https://%s%s", net.JoinHostPort(ns.Host(), strconv.Itoa(ns.Port)), ns.DoHPath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("query DoH nameserver %s: %w", ns, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH nameserver %s returned status %d", ns, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response from DoH nameserver %s: %w", ns, err)
+	}
+
+	reply := new(dns.Msg)
+	if err := reply.Unpack(body); err != nil {
+		return nil, fmt.Errorf("unpack response from DoH nameserver %s: %w", ns, err)
+	}
+	return reply, nil
+}
@@ -0,0 +1,157 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/miekg/dns"
+)
+
+// Resolver resolves a DNS query, optionally forwarding to the next resolver in a chain
+type Resolver interface {
+	Resolve(ctx context.Context, group *NameServerGroup, msg *dns.Msg) (*dns.Msg, error)
+}
+
+// ResolverFunc adapts a function to a Resolver
+type ResolverFunc func(ctx context.Context, group *NameServerGroup, msg *dns.Msg) (*dns.Msg, error)
+
+// Resolve implements Resolver
+func (f ResolverFunc) Resolve(ctx context.Context, group *NameServerGroup, msg *dns.Msg) (*dns.Msg, error) {
+	return f(ctx, group, msg)
+}
+
+// BlockingResolver short-circuits queries that match a NameServerGroup's Blocklists before they
+// reach the next Resolver in the chain (typically a caching resolver in front of the upstreams).
+type BlockingResolver struct {
+	next Resolver
+
+	mu   sync.RWMutex
+	sets map[string]*blockSet // keyed by NameServerGroup.ID
+}
+
+// NewBlockingResolver creates a BlockingResolver that forwards non-blocked queries to next
+func NewBlockingResolver(next Resolver) *BlockingResolver {
+	return &BlockingResolver{
+		next: next,
+		sets: make(map[string]*blockSet),
+	}
+}
+
+// UpdateBlockSet installs the compiled block/allow sets for a group, replacing any previous version.
+// Called by the refresher whenever a group's Blocklists are (re)loaded.
+func (b *BlockingResolver) UpdateBlockSet(groupID string, set *blockSet) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sets[groupID] = set
+}
+
+// Resolve blocks the query if its name matches the group's blocklists and isn't allow-listed,
+// otherwise forwards it to the next resolver in the chain
+func (b *BlockingResolver) Resolve(ctx context.Context, group *NameServerGroup, msg *dns.Msg) (*dns.Msg, error) {
+	if len(group.Blocklists) == 0 || len(msg.Question) == 0 {
+		return b.next.Resolve(ctx, group, msg)
+	}
+
+	b.mu.RLock()
+	set := b.sets[group.ID]
+	b.mu.RUnlock()
+
+	qname := strings.TrimSuffix(strings.ToLower(msg.Question[0].Name), ".")
+	if set == nil || !set.blocks(qname) || set.allows(qname) {
+		return b.next.Resolve(ctx, group, msg)
+	}
+
+	return blockedResponse(group, msg), nil
+}
+
+func blockedResponse(group *NameServerGroup, msg *dns.Msg) *dns.Msg {
+	resp := new(dns.Msg)
+	resp.SetReply(msg)
+
+	strategy := group.BlockStrategy
+	if strategy == "" {
+		strategy = NXDomainBlockStrategy
+	}
+
+	switch strategy {
+	case NXDomainBlockStrategy:
+		resp.Rcode = dns.RcodeNameError
+	case ZeroIPBlockStrategy, CustomIPBlockStrategy:
+		resp.Rcode = dns.RcodeSuccess
+		if len(msg.Question) > 0 {
+			if rr := blockedRR(msg.Question[0], strategy, group.BlockCustomIP); rr != nil {
+				resp.Answer = append(resp.Answer, rr)
+			}
+		}
+	}
+
+	return resp
+}
+
+// blockedRR builds the A or AAAA record answering q under ZeroIPBlockStrategy or
+// CustomIPBlockStrategy, matching q.Qtype so an AAAA question gets an AAAA answer (0.0.0.0 is
+// meaningless there) instead of an A record every resolver would discard as non-responsive. nil
+// is returned for question types neither strategy answers.
+func blockedRR(q dns.Question, strategy BlockStrategy, customIP string) dns.RR {
+	var custom net.IP
+	if strategy == CustomIPBlockStrategy && customIP != "" {
+		custom = net.ParseIP(customIP)
+	}
+
+	switch q.Qtype {
+	case dns.TypeAAAA:
+		ip := net.IPv6zero
+		if custom != nil && custom.To4() == nil {
+			ip = custom
+		}
+		return &dns.AAAA{
+			Hdr:  dns.RR_Header{Name: q.Name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: 60},
+			AAAA: ip,
+		}
+	case dns.TypeA:
+		ip := net.IPv4zero
+		if custom != nil && custom.To4() != nil {
+			ip = custom
+		}
+		return &dns.A{
+			Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+			A:   ip,
+		}
+	default:
+		return nil
+	}
+}
+
+// blockSet is the compiled, lookup-ready representation of a group's Blocklists and Allowlist
+type blockSet struct {
+	domains  map[string]struct{}
+	allow    map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+func newBlockSet() *blockSet {
+	return &blockSet{
+		domains: make(map[string]struct{}),
+		allow:   make(map[string]struct{}),
+	}
+}
+
+func (s *blockSet) blocks(qname string) bool {
+	if _, ok := s.domains[qname]; ok {
+		return true
+	}
+	for _, re := range s.patterns {
+		if re.MatchString(qname) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *blockSet) allows(qname string) bool {
+	_, ok := s.allow[qname]
+	return ok
+}
@@ -0,0 +1,39 @@
+package dns
+
+// BlocklistSourceKind identifies the format of a Blocklist's Source
+type BlocklistSourceKind string
+
+const (
+	// HostsBlocklistSource is a hosts-file formatted list ("0.0.0.0 ads.example.com")
+	HostsBlocklistSource BlocklistSourceKind = "hosts"
+	// DomainBlocklistSource is a plain newline-separated list of domains
+	DomainBlocklistSource BlocklistSourceKind = "domains"
+	// RegexBlocklistSource is a newline-separated list of regular expressions matched against the query name
+	RegexBlocklistSource BlocklistSourceKind = "regex"
+)
+
+// BlockStrategy controls how a BlockingResolver answers a blocked query
+type BlockStrategy string
+
+const (
+	// NXDomainBlockStrategy answers blocked queries with NXDOMAIN
+	NXDomainBlockStrategy BlockStrategy = "nxdomain"
+	// ZeroIPBlockStrategy answers blocked queries with 0.0.0.0 / ::
+	ZeroIPBlockStrategy BlockStrategy = "zeroip"
+	// CustomIPBlockStrategy answers blocked queries with NameServerGroup.BlockCustomIP
+	CustomIPBlockStrategy BlockStrategy = "custom-ip"
+)
+
+// Blocklist is a single remote or inline list of domains to block for a NameServerGroup
+type Blocklist struct {
+	ID string
+	// Source is the format of the list fetched from URL
+	Source BlocklistSourceKind
+	// URL is fetched and refreshed periodically; mutually exclusive with inline Entries
+	URL string
+	// Entries holds inline domains/patterns when URL is empty
+	Entries []string
+	// ETag is an ETag value for URL. BlocklistRefresher neither reads nor sets it: it keeps the
+	// last ETag it saw for URL in its BlocklistCacheStore. Last-Modified is not tracked.
+	ETag string
+}
@@ -0,0 +1,161 @@
+package dns
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultBlocklistRefreshInterval is how often remote Blocklists are re-fetched
+const DefaultBlocklistRefreshInterval = 1 * time.Hour
+
+// BlocklistCacheStore persists the ETag and raw body last fetched for a Blocklist.URL, so that
+// refreshes can send conditional requests instead of re-downloading unchanged lists every cycle,
+// while still being able to rebuild the compiled blockSet from the cached body on a 304.
+type BlocklistCacheStore interface {
+	GetBlocklistETag(groupID, blocklistID string) (string, error)
+	SaveBlocklistETag(groupID, blocklistID, etag string) error
+	GetBlocklistBody(groupID, blocklistID string) ([]byte, error)
+	SaveBlocklistBody(groupID, blocklistID string, body []byte) error
+}
+
+// BlocklistRefresher periodically fetches a NameServerGroup's Blocklists and installs the
+// compiled result into a BlockingResolver
+type BlocklistRefresher struct {
+	resolver *BlockingResolver
+	store    BlocklistCacheStore
+	client   *http.Client
+	interval time.Duration
+}
+
+// NewBlocklistRefresher creates a BlocklistRefresher backed by the given ETag cache store
+func NewBlocklistRefresher(resolver *BlockingResolver, store BlocklistCacheStore) *BlocklistRefresher {
+	return &BlocklistRefresher{
+		resolver: resolver,
+		store:    store,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		interval: DefaultBlocklistRefreshInterval,
+	}
+}
+
+// Run refreshes group's blocklists every interval until ctx is canceled
+func (r *BlocklistRefresher) Run(ctx context.Context, group *NameServerGroup) {
+	r.refresh(ctx, group)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.refresh(ctx, group)
+		}
+	}
+}
+
+func (r *BlocklistRefresher) refresh(ctx context.Context, group *NameServerGroup) {
+	set := newBlockSet()
+	for _, entry := range group.Allowlist {
+		set.allow[normalizeDomain(entry)] = struct{}{}
+	}
+
+	for i := range group.Blocklists {
+		bl := &group.Blocklists[i]
+		if err := r.loadBlocklist(ctx, group.ID, bl, set); err != nil {
+			log.Warnf("failed to refresh blocklist %s for nameserver group %s: %s", bl.ID, group.ID, err)
+		}
+	}
+
+	r.resolver.UpdateBlockSet(group.ID, set)
+}
+
+func (r *BlocklistRefresher) loadBlocklist(ctx context.Context, groupID string, bl *Blocklist, set *blockSet) error {
+	for _, entry := range bl.Entries {
+		addBlocklistEntry(set, bl.Source, entry)
+	}
+
+	if bl.URL == "" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bl.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	if etag, err := r.store.GetBlocklistETag(groupID, bl.ID); err == nil && etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		body, err := r.store.GetBlocklistBody(groupID, bl.ID)
+		if err != nil {
+			return fmt.Errorf("blocklist %s not modified but no cached body available: %w", bl.ID, err)
+		}
+		addBlocklistLines(set, bl.Source, body)
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	addBlocklistLines(set, bl.Source, body)
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		if err := r.store.SaveBlocklistETag(groupID, bl.ID, etag); err != nil {
+			return err
+		}
+	}
+	return r.store.SaveBlocklistBody(groupID, bl.ID, body)
+}
+
+func addBlocklistLines(set *blockSet, kind BlocklistSourceKind, body []byte) {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		addBlocklistEntry(set, kind, scanner.Text())
+	}
+}
+
+func addBlocklistEntry(set *blockSet, kind BlocklistSourceKind, line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+
+	switch kind {
+	case HostsBlocklistSource:
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return
+		}
+		set.domains[normalizeDomain(fields[1])] = struct{}{}
+	case RegexBlocklistSource:
+		re, err := regexp.Compile(line)
+		if err != nil {
+			return
+		}
+		set.patterns = append(set.patterns, re)
+	default: // DomainBlocklistSource
+		set.domains[normalizeDomain(line)] = struct{}{}
+	}
+}
+
+func normalizeDomain(domain string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+}
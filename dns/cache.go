@@ -0,0 +1,168 @@
+package dns
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DefaultCacheSize is the maximum number of entries kept per NameServerGroup in the CachingResolver
+const DefaultCacheSize = 5000
+
+// minCacheTTL is the lowest TTL (in seconds) a cached entry is allowed to have, preventing
+// a misbehaving upstream with TTL=0 from defeating the cache entirely
+const minCacheTTL = 5
+
+// CachingResolver caches upstream responses per NameServerGroup, keyed by (qname, qtype), clamped
+// between minCacheTTL and the group's CacheMaxTTL. It sits in front of the upstream-forwarding
+// Resolver in the chain.
+type CachingResolver struct {
+	next Resolver
+	size int
+
+	mu     sync.Mutex
+	groups map[string]*groupCache
+}
+
+// NewCachingResolver creates a CachingResolver with the default per-group cache size
+func NewCachingResolver(next Resolver) *CachingResolver {
+	return &CachingResolver{
+		next:   next,
+		size:   DefaultCacheSize,
+		groups: make(map[string]*groupCache),
+	}
+}
+
+// Resolve returns a cached response if one is fresh for the group, otherwise forwards to next
+// and, when the group has caching enabled, stores the result for subsequent lookups
+func (c *CachingResolver) Resolve(ctx context.Context, group *NameServerGroup, msg *dns.Msg) (*dns.Msg, error) {
+	if !group.CacheEnabled || len(msg.Question) == 0 {
+		return c.next.Resolve(ctx, group, msg)
+	}
+
+	key := cacheKey(msg.Question[0])
+	cache := c.groupCacheFor(group.ID)
+
+	if resp, ok := cache.get(key); ok {
+		reply := resp.Copy()
+		reply.Id = msg.Id
+		return reply, nil
+	}
+
+	resp, err := c.next.Resolve(ctx, group, msg)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	cache.set(key, resp, clampTTL(resp, group.CacheMaxTTL))
+	return resp, nil
+}
+
+// Flush discards every cached entry for a NameServerGroup
+func (c *CachingResolver) Flush(groupID string) {
+	c.mu.Lock()
+	delete(c.groups, groupID)
+	c.mu.Unlock()
+}
+
+func (c *CachingResolver) groupCacheFor(groupID string) *groupCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cache, ok := c.groups[groupID]
+	if !ok {
+		cache = newGroupCache(c.size)
+		c.groups[groupID] = cache
+	}
+	return cache
+}
+
+func cacheKey(q dns.Question) string {
+	return q.Name + "|" + dns.TypeToString[q.Qtype]
+}
+
+// clampTTL returns how long to cache resp for: the shortest TTL among its answer RRs (per RFC
+// 2181 §5.2, since that's when the first record in the RRset legitimately expires), clamped
+// between minCacheTTL and the group's CacheMaxTTL.
+func clampTTL(resp *dns.Msg, maxTTL int) time.Duration {
+	var ttl uint32
+	for i, rr := range resp.Answer {
+		if i == 0 || rr.Header().Ttl < ttl {
+			ttl = rr.Header().Ttl
+		}
+	}
+	if ttl < minCacheTTL {
+		ttl = minCacheTTL
+	}
+	if maxTTL > 0 && ttl > uint32(maxTTL) {
+		ttl = uint32(maxTTL)
+	}
+	return time.Duration(ttl) * time.Second
+}
+
+// groupCache is a size-bounded LRU cache of DNS responses for a single NameServerGroup
+type groupCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type cacheEntry struct {
+	key      string
+	response *dns.Msg
+	expires  time.Time
+}
+
+func newGroupCache(size int) *groupCache {
+	return &groupCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *groupCache) get(key string) (*dns.Msg, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	return entry.response, true
+}
+
+func (c *groupCache) set(key string, resp *dns.Msg, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+	}
+
+	entry := &cacheEntry{key: key, response: resp.Copy(), expires: time.Now().Add(ttl)}
+	c.entries[key] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
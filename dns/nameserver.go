@@ -0,0 +1,188 @@
+package dns
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/netbirdio/netbird/management/server/status"
+)
+
+const (
+	// DefaultDNSPort is the default port that NetBird's DNS resolver listens on
+	DefaultDNSPort = 53
+	// DefaultDoTPort is the default port for DNS-over-TLS (RFC 7858) upstreams
+	DefaultDoTPort = 853
+	// DefaultDoHPort is the default port for DNS-over-HTTPS (RFC 8484) upstreams
+	DefaultDoHPort = 443
+
+	minPort = 1
+	maxPort = 65535
+)
+
+// NameServerType represents the type (protocol) of an upstream NameServer
+type NameServerType int32
+
+const (
+	// UDPNameServerType UDP nameserver type
+	UDPNameServerType NameServerType = iota
+	// TCPNameServerType TCP nameserver type
+	TCPNameServerType
+	// DoHNameServerType DNS-over-HTTPS (RFC 8484) nameserver type
+	DoHNameServerType
+	// DoTNameServerType DNS-over-TLS (RFC 7858) nameserver type
+	DoTNameServerType
+)
+
+// String returns human-readable, URL-scheme compatible representation of a NameServerType
+func (n NameServerType) String() string {
+	switch n {
+	case UDPNameServerType:
+		return "udp"
+	case TCPNameServerType:
+		return "tcp"
+	case DoHNameServerType:
+		return "https"
+	case DoTNameServerType:
+		return "tls"
+	default:
+		return "unsupported"
+	}
+}
+
+// NameServerTypeFromString parses the scheme part of a nameserver URL into a NameServerType
+func NameServerTypeFromString(typeString string) (NameServerType, error) {
+	switch typeString {
+	case "udp":
+		return UDPNameServerType, nil
+	case "tcp":
+		return TCPNameServerType, nil
+	case "https":
+		return DoHNameServerType, nil
+	case "tls":
+		return DoTNameServerType, nil
+	default:
+		return 0, status.Errorf(status.InvalidArgument, "unknown nameserver type %s", typeString)
+	}
+}
+
+// NameServer represents a DNS nameserver combined with a connection protocol.
+// DoHPath only applies to DoHNameServerType; Hostname only applies to DoHNameServerType and
+// DoTNameServerType.
+type NameServer struct {
+	// IP is the upstream's address. Left zero-valued when DoHNameServerType or DoTNameServerType
+	// is configured by hostname instead (e.g. NextDNS, Google), in which case Hostname is used
+	// both to dial and to validate the certificate.
+	IP     netip.Addr
+	NSType NameServerType
+	Port   int
+	// DoHPath is the HTTP path of a DoH upstream, e.g. "/dns-query". Only set for DoHNameServerType.
+	DoHPath string
+	// Hostname is the DoH/DoT upstream's DNS name. For a hostname-only upstream (no stable IP)
+	// it's also what gets dialed; for a DoT IP upstream it overrides the SNI/certificate name
+	// presented to TLS validation. Only set for DoHNameServerType and DoTNameServerType.
+	Hostname string
+}
+
+// Host returns the value to dial: the literal IP if one was configured, otherwise Hostname
+func (n NameServer) Host() string {
+	if n.IP.IsValid() {
+		return n.IP.String()
+	}
+	return n.Hostname
+}
+
+// ParseNameServerURL parses a nameserver URL into a NameServer.
+// Accepted forms are "udp://ip:port", "tcp://ip:port",
+// "tls://ip:port?hostname=sni.example.com" or "tls://dns.example.com:port",
+// and "https://ip:port/dns-query" or "https://dns.nextdns.io/<id>".
+func ParseNameServerURL(nsURL string) (NameServer, error) {
+	parsedURL, err := url.Parse(nsURL)
+	if err != nil {
+		return NameServer{}, status.Errorf(status.InvalidArgument, "invalid nameserver url %s: %s", nsURL, err)
+	}
+
+	var ns NameServer
+
+	ns.NSType, err = NameServerTypeFromString(parsedURL.Scheme)
+	if err != nil {
+		return NameServer{}, err
+	}
+
+	host := parsedURL.Hostname()
+	ip, ipErr := netip.ParseAddr(host)
+	switch ns.NSType {
+	case DoHNameServerType, DoTNameServerType:
+		if ipErr == nil {
+			ns.IP = ip
+		} else {
+			ns.Hostname = host
+		}
+	default:
+		if ipErr != nil {
+			return NameServer{}, status.Errorf(status.InvalidArgument, "invalid nameserver IP %s", host)
+		}
+		ns.IP = ip
+	}
+
+	portStr := parsedURL.Port()
+	if portStr == "" {
+		ns.Port = defaultPortFor(ns.NSType)
+	} else {
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return NameServer{}, status.Errorf(status.InvalidArgument, "invalid nameserver port %s", portStr)
+		}
+		ns.Port = port
+	}
+
+	if ns.Port < minPort || ns.Port > maxPort {
+		return NameServer{}, status.Errorf(status.InvalidArgument, "nameserver port %d out of range", ns.Port)
+	}
+
+	switch ns.NSType {
+	case DoHNameServerType:
+		ns.DoHPath = parsedURL.Path
+		if stringIsEmpty(ns.DoHPath) {
+			ns.DoHPath = "/dns-query"
+		}
+	case DoTNameServerType:
+		if ns.Hostname == "" {
+			ns.Hostname = parsedURL.Query().Get("hostname")
+		}
+	}
+
+	return ns, nil
+}
+
+func defaultPortFor(nsType NameServerType) int {
+	switch nsType {
+	case DoHNameServerType:
+		return DefaultDoHPort
+	case DoTNameServerType:
+		return DefaultDoTPort
+	default:
+		return DefaultDNSPort
+	}
+}
+
+// String returns the URL representation of the NameServer
+func (n NameServer) String() string {
+	switch n.NSType {
+	case DoHNameServerType:
+		return fmt.Sprintf("%s://%s:%d%s", n.NSType.String(), n.Host(), n.Port, n.DoHPath)
+	case DoTNameServerType:
+		if stringIsEmpty(n.Hostname) || n.Hostname == n.Host() {
+			return fmt.Sprintf("%s://%s:%d", n.NSType.String(), n.Host(), n.Port)
+		}
+		return fmt.Sprintf("%s://%s:%d?hostname=%s", n.NSType.String(), n.Host(), n.Port, n.Hostname)
+	default:
+		return fmt.Sprintf("%s://%s:%d", n.NSType.String(), n.Host(), n.Port)
+	}
+}
+
+func stringIsEmpty(s string) bool {
+	return len(strings.TrimSpace(s)) == 0
+}
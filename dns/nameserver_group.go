@@ -0,0 +1,73 @@
+package dns
+
+// NameServerGroup represents a group of nameservers and a set of domains that use those nameservers
+type NameServerGroup struct {
+	ID          string
+	Name        string
+	Description string
+	// Primary indicates whether this is the primary nameserver group that's used for the main domain
+	Primary bool
+	// Domains is the list of domains that should be resolved via this group's NameServers.
+	// Ignored when Primary is true.
+	Domains []string
+	// NameServers is the list of upstream nameservers for this group
+	NameServers []NameServer
+	// Groups are the distribution groups of peers that should use this nameserver group
+	Groups []string
+	// Enabled indicates whether the group is active
+	Enabled bool
+	// SearchDomainsEnabled indicates whether Domains should also be used as search domains
+	SearchDomainsEnabled bool
+	// Blocklists are the ad/tracker blocklists applied to queries resolved through this group
+	Blocklists []Blocklist
+	// Allowlist domains are never blocked, even if present in a Blocklist
+	Allowlist []string
+	// BlockStrategy controls how a blocked query is answered. Defaults to NXDomainBlockStrategy.
+	BlockStrategy BlockStrategy
+	// BlockCustomIP is the response IP used when BlockStrategy is CustomIPBlockStrategy
+	BlockCustomIP string
+	// CacheEnabled turns on response caching for queries resolved through this group
+	CacheEnabled bool
+	// CacheMaxTTL clamps the TTL (in seconds) of cached responses; 0 means no clamp
+	CacheMaxTTL int
+	// QueryLogEnabled turns on persisting resolved queries for this group
+	QueryLogEnabled bool
+	// QueryLogRetentionDays is how long query log entries are kept before being pruned
+	QueryLogRetentionDays int
+}
+
+// Copy returns a deep copy of the NameServerGroup: every slice field gets its own backing array,
+// so mutating the copy's slices never affects the receiver's.
+func (g *NameServerGroup) Copy() *NameServerGroup {
+	return &NameServerGroup{
+		ID:                    g.ID,
+		Name:                  g.Name,
+		Description:           g.Description,
+		Primary:               g.Primary,
+		Domains:               append([]string(nil), g.Domains...),
+		NameServers:           append([]NameServer(nil), g.NameServers...),
+		Groups:                append([]string(nil), g.Groups...),
+		Enabled:               g.Enabled,
+		SearchDomainsEnabled:  g.SearchDomainsEnabled,
+		Blocklists:            copyBlocklists(g.Blocklists),
+		Allowlist:             append([]string(nil), g.Allowlist...),
+		BlockStrategy:         g.BlockStrategy,
+		BlockCustomIP:         g.BlockCustomIP,
+		CacheEnabled:          g.CacheEnabled,
+		CacheMaxTTL:           g.CacheMaxTTL,
+		QueryLogEnabled:       g.QueryLogEnabled,
+		QueryLogRetentionDays: g.QueryLogRetentionDays,
+	}
+}
+
+func copyBlocklists(blocklists []Blocklist) []Blocklist {
+	if blocklists == nil {
+		return nil
+	}
+	out := make([]Blocklist, len(blocklists))
+	for i, bl := range blocklists {
+		out[i] = bl
+		out[i].Entries = append([]string(nil), bl.Entries...)
+	}
+	return out
+}
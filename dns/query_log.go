@@ -0,0 +1,121 @@
+package dns
+
+import (
+	"context"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DefaultQueryLogRetentionDays is used when a NameServerGroup enables query logging without
+// specifying QueryLogRetentionDays
+const DefaultQueryLogRetentionDays = 7
+
+// QueryLogEntry is a single resolved query recorded for a NameServerGroup
+type QueryLogEntry struct {
+	GroupID    string
+	PeerID     string
+	Question   string
+	QType      string
+	Rcode      string
+	LatencyMs  int64
+	Upstream   string
+	ResolvedAt time.Time
+}
+
+// QueryLogStore persists and prunes QueryLogEntry records. Implemented against the management DB.
+type QueryLogStore interface {
+	Append(ctx context.Context, entry QueryLogEntry) error
+	List(ctx context.Context, groupID string, pageToken string, pageSize int) (entries []QueryLogEntry, nextPageToken string, err error)
+	DeleteOlderThan(ctx context.Context, groupID string, before time.Time) error
+}
+
+// QueryLoggingResolver records every query resolved for a group whose QueryLogEnabled is set,
+// then forwards to the next Resolver in the chain
+type QueryLoggingResolver struct {
+	next  Resolver
+	store QueryLogStore
+}
+
+// NewQueryLoggingResolver creates a QueryLoggingResolver backed by the given QueryLogStore
+func NewQueryLoggingResolver(next Resolver, store QueryLogStore) *QueryLoggingResolver {
+	return &QueryLoggingResolver{next: next, store: store}
+}
+
+// peerIDContextKey is the context key under which WithPeerID stores the resolving peer's ID,
+// set by the DNS server before dispatch and read back by peerIDFromContext
+type peerIDContextKey struct{}
+
+// WithPeerID returns a context carrying the peer ID of the client that issued the query
+func WithPeerID(ctx context.Context, peerID string) context.Context {
+	return context.WithValue(ctx, peerIDContextKey{}, peerID)
+}
+
+func peerIDFromContext(ctx context.Context) string {
+	peerID, _ := ctx.Value(peerIDContextKey{}).(string)
+	return peerID
+}
+
+// upstreamRecorderKey is the context key a QueryLoggingResolver uses to learn which upstream
+// nameserver ultimately answered a query, several layers down the resolution chain
+type upstreamRecorderKey struct{}
+
+// WithUpstreamRecorder returns a context carrying a recorder that the chain's innermost
+// Resolver (the one actually dialing an upstream) can fill in via RecordUpstream, and a pointer
+// the caller reads back once Resolve returns
+func WithUpstreamRecorder(ctx context.Context) (context.Context, *string) {
+	upstream := new(string)
+	return context.WithValue(ctx, upstreamRecorderKey{}, upstream), upstream
+}
+
+// RecordUpstream reports the upstream nameserver that answered a query, if ctx carries a
+// recorder installed by WithUpstreamRecorder. It's a no-op otherwise, so resolvers can call it
+// unconditionally regardless of whether query logging is enabled for the group.
+func RecordUpstream(ctx context.Context, upstream string) {
+	if ptr, ok := ctx.Value(upstreamRecorderKey{}).(*string); ok {
+		*ptr = upstream
+	}
+}
+
+// Resolve forwards the query, then asynchronously persists a QueryLogEntry describing the result
+func (q *QueryLoggingResolver) Resolve(ctx context.Context, group *NameServerGroup, msg *dns.Msg) (*dns.Msg, error) {
+	if !group.QueryLogEnabled || len(msg.Question) == 0 {
+		return q.next.Resolve(ctx, group, msg)
+	}
+
+	start := time.Now()
+	recCtx, upstream := WithUpstreamRecorder(ctx)
+	resp, err := q.next.Resolve(recCtx, group, msg)
+
+	entry := QueryLogEntry{
+		GroupID:    group.ID,
+		PeerID:     peerIDFromContext(ctx),
+		Question:   msg.Question[0].Name,
+		QType:      dns.TypeToString[msg.Question[0].Qtype],
+		LatencyMs:  time.Since(start).Milliseconds(),
+		Upstream:   *upstream,
+		ResolvedAt: start,
+	}
+	if resp != nil {
+		entry.Rcode = dns.RcodeToString[resp.Rcode]
+	}
+
+	go func() {
+		logCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = q.store.Append(logCtx, entry)
+	}()
+
+	return resp, err
+}
+
+// PruneQueryLogs deletes log entries older than QueryLogRetentionDays for group. Intended to run
+// on a periodic background ticker per enabled group.
+func PruneQueryLogs(ctx context.Context, store QueryLogStore, group *NameServerGroup) error {
+	retentionDays := group.QueryLogRetentionDays
+	if retentionDays <= 0 {
+		retentionDays = DefaultQueryLogRetentionDays
+	}
+	before := time.Now().AddDate(0, 0, -retentionDays)
+	return store.DeleteOlderThan(ctx, group.ID, before)
+}
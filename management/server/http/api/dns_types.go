@@ -0,0 +1,176 @@
+package api
+
+// NameserverNsType defines the connection protocol/scheme of a Nameserver
+type NameserverNsType string
+
+// Defined values for NameserverNsType
+const (
+	NameserverNsTypeUdp   NameserverNsType = "udp"
+	NameserverNsTypeTcp   NameserverNsType = "tcp"
+	NameserverNsTypeHttps NameserverNsType = "https"
+	NameserverNsTypeTls   NameserverNsType = "tls"
+)
+
+// Nameserver defines model for Nameserver
+type Nameserver struct {
+	// Ip is the nameserver's address. For a DoH/DoT (NsType "https"/"tls") upstream with no
+	// stable IP (e.g. NextDNS, Google), this may instead be the upstream's hostname.
+	Ip string `json:"ip"`
+
+	// NsType is the connection protocol used to reach the nameserver
+	NsType NameserverNsType `json:"ns_type"`
+
+	// Port is the nameserver port
+	Port int `json:"port"`
+
+	// DohPath is the HTTP path queried on a DoH (NsType "https") upstream, e.g. "/dns-query"
+	DohPath *string `json:"doh_path,omitempty"`
+
+	// Hostname is the SNI/certificate hostname validated against a DoT (NsType "tls") upstream.
+	// Only needed when Ip is a literal IP and the certificate name differs from it.
+	Hostname *string `json:"hostname,omitempty"`
+}
+
+// NameserverGroup defines model for NameserverGroup
+type NameserverGroup struct {
+	// Id is the unique identifier of the nameserver group
+	Id string `json:"id"`
+
+	// Name is the name of the nameserver group
+	Name string `json:"name"`
+
+	// Description is a free-text description of the nameserver group
+	Description string `json:"description"`
+
+	// Primary indicates whether this is the primary nameserver group
+	Primary bool `json:"primary"`
+
+	// Domains is the list of domains resolved via this group, ignored when Primary is true
+	Domains []string `json:"domains"`
+
+	// Nameservers is the list of nameservers in the group
+	Nameservers []Nameserver `json:"nameservers"`
+
+	// Groups are the distribution groups that use this nameserver group
+	Groups []string `json:"groups"`
+
+	// Enabled indicates whether the nameserver group is enabled
+	Enabled bool `json:"enabled"`
+
+	// SearchDomainsEnabled indicates whether Domains are also used as search domains
+	SearchDomainsEnabled bool `json:"search_domains_enabled"`
+
+	// Blocklists are the ad/tracker blocklists applied to queries resolved through this group
+	Blocklists []Blocklist `json:"blocklists,omitempty"`
+
+	// Allowlist domains are never blocked, even if present in a Blocklist
+	Allowlist []string `json:"allowlist,omitempty"`
+
+	// BlockStrategy controls how a blocked query is answered
+	BlockStrategy BlockStrategy `json:"block_strategy,omitempty"`
+
+	// CacheEnabled turns on response caching for queries resolved through this group
+	CacheEnabled bool `json:"cache_enabled"`
+
+	// CacheMaxTtl clamps the TTL (in seconds) of cached responses; 0 means no clamp
+	CacheMaxTtl int `json:"cache_max_ttl"`
+
+	// QueryLogEnabled turns on persisting resolved queries for this group
+	QueryLogEnabled bool `json:"query_log_enabled"`
+
+	// QueryLogRetentionDays is how long query log entries are kept before being pruned
+	QueryLogRetentionDays int `json:"query_log_retention_days"`
+}
+
+// NameserverGroupRequest defines model for NameserverGroupRequest
+type NameserverGroupRequest struct {
+	Name                 string       `json:"name"`
+	Description          string       `json:"description"`
+	Primary              bool         `json:"primary"`
+	Domains              []string     `json:"domains"`
+	Nameservers          []Nameserver `json:"nameservers"`
+	Groups               []string     `json:"groups"`
+	Enabled              bool         `json:"enabled"`
+	SearchDomainsEnabled bool         `json:"search_domains_enabled"`
+
+	CacheEnabled          bool `json:"cache_enabled"`
+	CacheMaxTtl           int  `json:"cache_max_ttl"`
+	QueryLogEnabled       bool `json:"query_log_enabled"`
+	QueryLogRetentionDays int  `json:"query_log_retention_days"`
+}
+
+// BlocklistSourceKind defines the format of a Blocklist's source
+type BlocklistSourceKind string
+
+// Defined values for BlocklistSourceKind
+const (
+	BlocklistSourceKindHosts   BlocklistSourceKind = "hosts"
+	BlocklistSourceKindDomains BlocklistSourceKind = "domains"
+	BlocklistSourceKindRegex   BlocklistSourceKind = "regex"
+)
+
+// BlockStrategy defines how a blocked query is answered
+type BlockStrategy string
+
+// Defined values for BlockStrategy
+const (
+	BlockStrategyNxdomain BlockStrategy = "nxdomain"
+	BlockStrategyZeroip   BlockStrategy = "zeroip"
+	BlockStrategyCustomIp BlockStrategy = "custom-ip"
+)
+
+// Blocklist defines model for Blocklist
+type Blocklist struct {
+	Id      string              `json:"id"`
+	Source  BlocklistSourceKind `json:"source"`
+	Url     string              `json:"url,omitempty"`
+	Entries []string            `json:"entries,omitempty"`
+}
+
+// QueryLogEntry defines model for a single resolved DNS query
+type QueryLogEntry struct {
+	PeerId     string `json:"peer_id"`
+	Question   string `json:"question"`
+	QType      string `json:"q_type"`
+	Rcode      string `json:"rcode"`
+	LatencyMs  int64  `json:"latency_ms"`
+	Upstream   string `json:"upstream"`
+	ResolvedAt string `json:"resolved_at"`
+}
+
+// QueryLogResponse defines model for a page of QueryLogEntry results
+type QueryLogResponse struct {
+	Entries       []QueryLogEntry `json:"entries"`
+	NextPageToken string          `json:"next_page_token,omitempty"`
+}
+
+// BatchDeleteNameserverGroupsRequest defines body for BatchDeleteNameserverGroups
+type BatchDeleteNameserverGroupsRequest struct {
+	Ids []string `json:"ids"`
+}
+
+// NameserverGroupBatchItem defines a single group within a BatchSaveNameserverGroupsRequest.
+// Id is empty when the group should be created.
+type NameserverGroupBatchItem struct {
+	Id string `json:"id,omitempty"`
+	NameserverGroupRequest
+}
+
+// BatchSaveNameserverGroupsRequest defines body for BatchSaveNameserverGroups
+type BatchSaveNameserverGroupsRequest struct {
+	NameserverGroups []NameserverGroupBatchItem `json:"nameserver_groups"`
+}
+
+// PutNameserverGroupBlocklistsRequest defines body for UpdateNameserverGroupBlocklists
+type PutNameserverGroupBlocklistsRequest struct {
+	Blocklists    []Blocklist   `json:"blocklists"`
+	Allowlist     []string      `json:"allowlist"`
+	BlockStrategy BlockStrategy `json:"block_strategy"`
+	BlockCustomIp string        `json:"block_custom_ip,omitempty"`
+}
+
+// PostApiDnsNameserversJSONRequestBody defines body for CreateNameserverGroup for application/json ContentType
+type PostApiDnsNameserversJSONRequestBody = NameserverGroupRequest
+
+// PutApiDnsNameserversNsgroupIdJSONRequestBody defines body for UpdateNameserverGroup for application/json ContentType
+type PutApiDnsNameserversNsgroupIdJSONRequestBody = NameserverGroupRequest
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	nbdns "github.com/netbirdio/netbird/dns"
@@ -14,6 +16,8 @@ import (
 	"github.com/netbirdio/netbird/management/server/status"
 )
 
+const defaultQueryLogPageSize = 100
+
 // NameserversHandler is the nameserver group handler of the account
 type NameserversHandler struct {
 	accountManager  server.AccountManager
@@ -66,6 +70,7 @@ func (h *NameserversHandler) CreateNameserverGroup(w http.ResponseWriter, r *htt
 	claims := h.claimsExtractor.FromRequestContext(r)
 	nsGroup, err := h.accountManager.CreateNameServerGroup(r.Context(), claims.AccountId, req.Name, req.Description,
 		nsList, req.Groups, req.Primary, req.Domains, req.Enabled, claims.UserId, req.SearchDomainsEnabled,
+		req.CacheEnabled, req.CacheMaxTtl, req.QueryLogEnabled, req.QueryLogRetentionDays,
 	)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
@@ -99,15 +104,19 @@ func (h *NameserversHandler) UpdateNameserverGroup(w http.ResponseWriter, r *htt
 	}
 
 	updatedNSGroup := &nbdns.NameServerGroup{
-		ID:                   nsGroupID,
-		Name:                 req.Name,
-		Description:          req.Description,
-		Primary:              req.Primary,
-		Domains:              req.Domains,
-		NameServers:          nsList,
-		Groups:               req.Groups,
-		Enabled:              req.Enabled,
-		SearchDomainsEnabled: req.SearchDomainsEnabled,
+		ID:                    nsGroupID,
+		Name:                  req.Name,
+		Description:           req.Description,
+		Primary:               req.Primary,
+		Domains:               req.Domains,
+		NameServers:           nsList,
+		Groups:                req.Groups,
+		Enabled:               req.Enabled,
+		SearchDomainsEnabled:  req.SearchDomainsEnabled,
+		CacheEnabled:          req.CacheEnabled,
+		CacheMaxTTL:           req.CacheMaxTtl,
+		QueryLogEnabled:       req.QueryLogEnabled,
+		QueryLogRetentionDays: req.QueryLogRetentionDays,
 	}
 
 	claims := h.claimsExtractor.FromRequestContext(r)
@@ -160,10 +169,210 @@ func (h *NameserversHandler) GetNameserverGroup(w http.ResponseWriter, r *http.R
 	util.WriteJSONObject(r.Context(), w, &resp)
 }
 
+// UpdateNameserverGroupBlocklists handles attaching blocklists, an allowlist and a block
+// strategy to the nameserver group identified by ID
+func (h *NameserversHandler) UpdateNameserverGroupBlocklists(w http.ResponseWriter, r *http.Request) {
+	nsGroupID := mux.Vars(r)["nsgroupId"]
+	if len(nsGroupID) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid nameserver group ID"), w)
+		return
+	}
+
+	var req api.PutNameserverGroupBlocklistsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	blockStrategy := nbdns.BlockStrategy(req.BlockStrategy)
+	if blockStrategy == "" {
+		blockStrategy = nbdns.NXDomainBlockStrategy
+	}
+	if blockStrategy == nbdns.CustomIPBlockStrategy && req.BlockCustomIp == "" {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "block_custom_ip is required for the custom-ip block strategy"), w)
+		return
+	}
+
+	claims := h.claimsExtractor.FromRequestContext(r)
+	nsGroup, err := h.accountManager.GetNameServerGroup(r.Context(), claims.AccountId, claims.UserId, nsGroupID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	nsGroup.Blocklists = toServerBlocklists(req.Blocklists)
+	nsGroup.Allowlist = req.Allowlist
+	nsGroup.BlockStrategy = blockStrategy
+	nsGroup.BlockCustomIP = req.BlockCustomIp
+
+	if err := h.accountManager.SaveNameServerGroup(r.Context(), claims.AccountId, claims.UserId, nsGroup); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	resp := toNameserverGroupResponse(nsGroup)
+
+	util.WriteJSONObject(r.Context(), w, &resp)
+}
+
+// BatchSaveNameserverGroups handles creating and updating several nameserver groups in a single
+// request, issuing one peer update instead of one per group
+func (h *NameserversHandler) BatchSaveNameserverGroups(w http.ResponseWriter, r *http.Request) {
+	var req api.BatchSaveNameserverGroupsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	nsGroups := make([]*nbdns.NameServerGroup, 0, len(req.NameserverGroups))
+	for _, item := range req.NameserverGroups {
+		nsList, err := toServerNSList(item.Nameservers)
+		if err != nil {
+			util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid NS servers format"), w)
+			return
+		}
+
+		nsGroups = append(nsGroups, &nbdns.NameServerGroup{
+			ID:                    item.Id,
+			Name:                  item.Name,
+			Description:           item.Description,
+			Primary:               item.Primary,
+			Domains:               item.Domains,
+			NameServers:           nsList,
+			Groups:                item.Groups,
+			Enabled:               item.Enabled,
+			SearchDomainsEnabled:  item.SearchDomainsEnabled,
+			CacheEnabled:          item.CacheEnabled,
+			CacheMaxTTL:           item.CacheMaxTtl,
+			QueryLogEnabled:       item.QueryLogEnabled,
+			QueryLogRetentionDays: item.QueryLogRetentionDays,
+		})
+	}
+
+	claims := h.claimsExtractor.FromRequestContext(r)
+	savedGroups, err := h.accountManager.SaveNameServerGroups(r.Context(), claims.AccountId, claims.UserId, nsGroups)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	resp := make([]*api.NameserverGroup, 0, len(savedGroups))
+	for _, g := range savedGroups {
+		resp = append(resp, toNameserverGroupResponse(g))
+	}
+
+	util.WriteJSONObject(r.Context(), w, resp)
+}
+
+// BatchDeleteNameserverGroups handles deleting several nameserver groups in a single request,
+// issuing one peer update instead of one per group
+func (h *NameserversHandler) BatchDeleteNameserverGroups(w http.ResponseWriter, r *http.Request) {
+	var req api.BatchDeleteNameserverGroupsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	if len(req.Ids) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "ids must not be empty"), w)
+		return
+	}
+
+	claims := h.claimsExtractor.FromRequestContext(r)
+	if err := h.accountManager.DeleteNameServerGroups(r.Context(), claims.AccountId, claims.UserId, req.Ids...); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, emptyObject{})
+}
+
+// GetNameserverGroupQueryLog returns a page of the resolved queries logged for the nameserver
+// group identified by ID
+func (h *NameserversHandler) GetNameserverGroupQueryLog(w http.ResponseWriter, r *http.Request) {
+	nsGroupID := mux.Vars(r)["nsgroupId"]
+	if len(nsGroupID) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid nameserver group ID"), w)
+		return
+	}
+
+	q := r.URL.Query()
+	pageSize := defaultQueryLogPageSize
+	if raw := q.Get("page_size"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+			pageSize = parsed
+		}
+	}
+
+	claims := h.claimsExtractor.FromRequestContext(r)
+	entries, nextPageToken, err := h.accountManager.GetNameServerGroupQueryLog(r.Context(), claims.AccountId, claims.UserId, nsGroupID, q.Get("page_token"), pageSize)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	resp := api.QueryLogResponse{NextPageToken: nextPageToken}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, api.QueryLogEntry{
+			PeerId:     e.PeerID,
+			Question:   e.Question,
+			QType:      e.QType,
+			Rcode:      e.Rcode,
+			LatencyMs:  e.LatencyMs,
+			Upstream:   e.Upstream,
+			ResolvedAt: e.ResolvedAt.Format(time.RFC3339),
+		})
+	}
+
+	util.WriteJSONObject(r.Context(), w, &resp)
+}
+
+// DeleteNameserverGroupCache flushes the response cache for the nameserver group identified by ID
+func (h *NameserversHandler) DeleteNameserverGroupCache(w http.ResponseWriter, r *http.Request) {
+	nsGroupID := mux.Vars(r)["nsgroupId"]
+	if len(nsGroupID) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid nameserver group ID"), w)
+		return
+	}
+
+	claims := h.claimsExtractor.FromRequestContext(r)
+	if err := h.accountManager.FlushNameServerGroupCache(r.Context(), claims.AccountId, claims.UserId, nsGroupID); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, emptyObject{})
+}
+
+func toServerBlocklists(apiBlocklists []api.Blocklist) []nbdns.Blocklist {
+	var blocklists []nbdns.Blocklist
+	for _, bl := range apiBlocklists {
+		blocklists = append(blocklists, nbdns.Blocklist{
+			ID:      bl.Id,
+			Source:  nbdns.BlocklistSourceKind(bl.Source),
+			URL:     bl.Url,
+			Entries: bl.Entries,
+		})
+	}
+	return blocklists
+}
+
 func toServerNSList(apiNSList []api.Nameserver) ([]nbdns.NameServer, error) {
 	var nsList []nbdns.NameServer
 	for _, apiNS := range apiNSList {
-		parsed, err := nbdns.ParseNameServerURL(fmt.Sprintf("%s://%s:%d", apiNS.NsType, apiNS.Ip, apiNS.Port))
+		nsURL := fmt.Sprintf("%s://%s:%d", apiNS.NsType, apiNS.Ip, apiNS.Port)
+		switch apiNS.NsType {
+		case api.NameserverNsTypeHttps:
+			if apiNS.DohPath != nil {
+				nsURL += *apiNS.DohPath
+			}
+		case api.NameserverNsTypeTls:
+			if apiNS.Hostname != nil && *apiNS.Hostname != "" {
+				nsURL += fmt.Sprintf("?hostname=%s", *apiNS.Hostname)
+			}
+		}
+
+		parsed, err := nbdns.ParseNameServerURL(nsURL)
 		if err != nil {
 			return nil, err
 		}
@@ -177,22 +386,45 @@ func toNameserverGroupResponse(serverNSGroup *nbdns.NameServerGroup) *api.Namese
 	var nsList []api.Nameserver
 	for _, ns := range serverNSGroup.NameServers {
 		apiNS := api.Nameserver{
-			Ip:     ns.IP.String(),
+			Ip:     ns.Host(),
 			NsType: api.NameserverNsType(ns.NSType.String()),
 			Port:   ns.Port,
 		}
+		if ns.NSType == nbdns.DoHNameServerType && ns.DoHPath != "" {
+			apiNS.DohPath = &ns.DoHPath
+		}
+		if ns.NSType == nbdns.DoTNameServerType && ns.Hostname != "" {
+			apiNS.Hostname = &ns.Hostname
+		}
 		nsList = append(nsList, apiNS)
 	}
 
+	var apiBlocklists []api.Blocklist
+	for _, bl := range serverNSGroup.Blocklists {
+		apiBlocklists = append(apiBlocklists, api.Blocklist{
+			Id:      bl.ID,
+			Source:  api.BlocklistSourceKind(bl.Source),
+			Url:     bl.URL,
+			Entries: bl.Entries,
+		})
+	}
+
 	return &api.NameserverGroup{
-		Id:                   serverNSGroup.ID,
-		Name:                 serverNSGroup.Name,
-		Description:          serverNSGroup.Description,
-		Primary:              serverNSGroup.Primary,
-		Domains:              serverNSGroup.Domains,
-		Groups:               serverNSGroup.Groups,
-		Nameservers:          nsList,
-		Enabled:              serverNSGroup.Enabled,
-		SearchDomainsEnabled: serverNSGroup.SearchDomainsEnabled,
+		Id:                    serverNSGroup.ID,
+		Name:                  serverNSGroup.Name,
+		Description:           serverNSGroup.Description,
+		Primary:               serverNSGroup.Primary,
+		Domains:               serverNSGroup.Domains,
+		Groups:                serverNSGroup.Groups,
+		Nameservers:           nsList,
+		Enabled:               serverNSGroup.Enabled,
+		SearchDomainsEnabled:  serverNSGroup.SearchDomainsEnabled,
+		Blocklists:            apiBlocklists,
+		Allowlist:             serverNSGroup.Allowlist,
+		BlockStrategy:         api.BlockStrategy(serverNSGroup.BlockStrategy),
+		CacheEnabled:          serverNSGroup.CacheEnabled,
+		CacheMaxTtl:           serverNSGroup.CacheMaxTTL,
+		QueryLogEnabled:       serverNSGroup.QueryLogEnabled,
+		QueryLogRetentionDays: serverNSGroup.QueryLogRetentionDays,
 	}
 }
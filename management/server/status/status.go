@@ -0,0 +1,49 @@
+// Package status provides a gRPC-like error type used across the management
+// server so that HTTP and gRPC layers can map internal errors to the right
+// status codes without leaking implementation details to callers.
+package status
+
+import "fmt"
+
+// Type represents a broad class of error, similar in spirit to gRPC codes
+type Type int
+
+const (
+	// Internal means an unexpected, non-recoverable error occurred
+	Internal Type = iota
+	// NotFound means the requested resource doesn't exist
+	NotFound
+	// InvalidArgument means the caller supplied a malformed or illegal argument
+	InvalidArgument
+	// PermissionDenied means the caller isn't allowed to perform the operation
+	PermissionDenied
+	// Unauthorized means the caller isn't authenticated
+	Unauthorized
+	// AlreadyExists means the resource the caller tried to create already exists
+	AlreadyExists
+)
+
+// Error is the error type returned by management server internals
+type Error struct {
+	Type    Type
+	Message string
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Errorf creates a new Error of the given Type with a formatted message
+func Errorf(errType Type, format string, a ...any) error {
+	return &Error{
+		Type:    errType,
+		Message: fmt.Sprintf(format, a...),
+	}
+}
+
+// FromError unwraps err into a status.Error, reporting ok=false if it isn't one
+func FromError(err error) (s *Error, ok bool) {
+	s, ok = err.(*Error)
+	return s, ok
+}
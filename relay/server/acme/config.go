@@ -0,0 +1,30 @@
+// Package acme manages automatic TLS certificate provisioning and renewal for the relay
+// server's WS listener, so operators no longer need to provision certificates out-of-band
+// and restart the relay on every renewal.
+package acme
+
+// Config configures automatic certificate management for a domain set. When set on
+// relay/server.Config, the WS listener's certificate is obtained and renewed automatically.
+type Config struct {
+	// Domains are the hostnames the certificate should cover
+	Domains []string
+	// Email is passed to the ACME directory for expiry/revocation notices
+	Email string
+	// DirectoryURL is the ACME directory endpoint. Defaults to Let's Encrypt's production directory.
+	DirectoryURL string
+	// CacheDir stores the account key and issued certificates across restarts for TLS-ALPN-01.
+	// With DNS01Provider set it stores only the account key and registration; the certificate
+	// is kept in memory and a new one is obtained on every start.
+	CacheDir string
+	// DNS01Provider configures DNS-01 validation. When nil, TLS-ALPN-01 is used on the same
+	// listener instead.
+	DNS01Provider *DNS01ProviderConfig
+}
+
+// DNS01ProviderConfig selects and authenticates a DNS-01 challenge provider
+type DNS01ProviderConfig struct {
+	// Name is one of "route53", "cloudflare", "gandi"
+	Name string
+	// Credentials holds provider-specific keys, e.g. "api_token" for Cloudflare
+	Credentials map[string]string
+}
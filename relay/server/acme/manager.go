@@ -0,0 +1,285 @@
+package acme
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/go-acme/lego/v4/registration"
+	log "github.com/sirupsen/logrus"
+	golangacme "golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// renewBefore is how long before expiry a DNS-01 certificate is renewed
+const renewBefore = 30 * 24 * time.Hour
+
+// renewCheckInterval is how often the DNS-01 renewal loop checks certificate expiry
+const renewCheckInterval = 12 * time.Hour
+
+// accountKeyFileName and accountRegFileName cache the relay's ACME account under Config.CacheDir
+// so obtainDNS01 doesn't generate a new key and re-register a new account with the CA on every
+// renewal tick
+const (
+	accountKeyFileName = "acme_account_key.pem"
+	accountRegFileName = "acme_account_reg.json"
+)
+
+// Manager obtains and renews a TLS certificate for Config.Domains, either via TLS-ALPN-01
+// (piggy-backing on the relay's own 443 listener) or DNS-01 (via a DNS01Provider), and exposes
+// a tls.Config whose GetCertificate hook always returns the current certificate without
+// requiring the listener to be closed and reopened on rotation.
+type Manager struct {
+	cfg Config
+
+	autocertMgr *autocert.Manager // set when Config.DNS01Provider is nil (TLS-ALPN-01)
+
+	mu   sync.RWMutex // guards cert, set when Config.DNS01Provider is set (DNS-01)
+	cert *tls.Certificate
+}
+
+// NewManager creates a Manager for cfg. When cfg.DNS01Provider is set it immediately obtains an
+// initial certificate via DNS-01 and starts a background renewal loop; otherwise certificates
+// are obtained lazily via TLS-ALPN-01 on first handshake.
+func NewManager(ctx context.Context, cfg Config) (*Manager, error) {
+	if len(cfg.Domains) == 0 {
+		return nil, errors.New("acme: at least one domain is required")
+	}
+
+	m := &Manager{cfg: cfg}
+
+	if cfg.DNS01Provider == nil {
+		m.autocertMgr = &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(cfg.CacheDir),
+			HostPolicy: autocert.HostWhitelist(cfg.Domains...),
+			Email:      cfg.Email,
+		}
+		if cfg.DirectoryURL != "" {
+			m.autocertMgr.Client = &golangacme.Client{DirectoryURL: cfg.DirectoryURL}
+		}
+		return m, nil
+	}
+
+	if err := m.obtainDNS01(ctx); err != nil {
+		return nil, fmt.Errorf("acme: initial DNS-01 certificate: %w", err)
+	}
+	go m.renewLoop(ctx)
+
+	return m, nil
+}
+
+// TLSConfig returns a tls.Config whose GetCertificate always serves the current certificate.
+// For TLS-ALPN-01 it also advertises the "acme-tls/1" protocol required to complete challenges.
+func (m *Manager) TLSConfig() *tls.Config {
+	if m.autocertMgr != nil {
+		return m.autocertMgr.TLSConfig()
+	}
+
+	return &tls.Config{
+		GetCertificate: m.getCertificateDNS01,
+	}
+}
+
+func (m *Manager) getCertificateDNS01(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.cert == nil {
+		return nil, errors.New("acme: no certificate obtained yet")
+	}
+	return m.cert, nil
+}
+
+func (m *Manager) renewLoop(ctx context.Context) {
+	ticker := time.NewTicker(renewCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.mu.RLock()
+			rawCert := m.cert.Certificate[0]
+			m.mu.RUnlock()
+
+			// Parsed explicitly rather than read from tls.Certificate.Leaf: Go only populates
+			// Leaf as a side effect of tls.X509KeyPair on 1.23+, so relying on it here would
+			// renew on every tick on older toolchains.
+			leaf, err := x509.ParseCertificate(rawCert)
+			if err != nil {
+				log.Errorf("acme: failed to parse current certificate: %s", err)
+				continue
+			}
+
+			if time.Until(leaf.NotAfter) > renewBefore {
+				continue
+			}
+
+			if err := m.obtainDNS01(ctx); err != nil {
+				log.Errorf("acme: failed to renew certificate via DNS-01: %s", err)
+			}
+		}
+	}
+}
+
+// acmeUser satisfies lego's registration.User so the relay can act as its own ACME account
+type acmeUser struct {
+	email        string
+	key          crypto.Signer
+	registration *registration.Resource
+}
+
+func (u *acmeUser) GetEmail() string                        { return u.email }
+func (u *acmeUser) GetRegistration() *registration.Resource { return u.registration }
+func (u *acmeUser) GetPrivateKey() crypto.Signer             { return u.key }
+
+func (m *Manager) obtainDNS01(ctx context.Context) error {
+	user, err := m.loadOrRegisterUser()
+	if err != nil {
+		return err
+	}
+
+	legoCfg := lego.NewConfig(user)
+	if m.cfg.DirectoryURL != "" {
+		legoCfg.CADirURL = m.cfg.DirectoryURL
+	}
+
+	client, err := lego.NewClient(legoCfg)
+	if err != nil {
+		return err
+	}
+
+	provider, err := NewDNSProvider(*m.cfg.DNS01Provider)
+	if err != nil {
+		return err
+	}
+	if err := client.Challenge.SetDNS01Provider(provider); err != nil {
+		return err
+	}
+
+	if user.registration == nil {
+		reg, err := client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+		if err != nil {
+			return err
+		}
+		user.registration = reg
+
+		if err := m.saveRegistration(reg); err != nil {
+			log.Warnf("acme: failed to cache account registration: %s", err)
+		}
+	}
+
+	req := certificate.ObtainRequest{
+		Domains: m.cfg.Domains,
+		Bundle:  true,
+	}
+	cert, err := client.Certificate.ObtainWithContext(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	tlsCert, err := tls.X509KeyPair(cert.Certificate, cert.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.cert = &tlsCert
+	m.mu.Unlock()
+
+	return nil
+}
+
+// loadOrRegisterUser returns the relay's ACME account, reusing the private key and registration
+// cached under Config.CacheDir when present rather than generating a fresh key (and leaving
+// obtainDNS01 to register a brand new account with the CA) on every call.
+func (m *Manager) loadOrRegisterUser() (*acmeUser, error) {
+	key, err := m.loadOrGenerateAccountKey()
+	if err != nil {
+		return nil, err
+	}
+
+	user := &acmeUser{email: m.cfg.Email, key: key}
+	if reg, err := m.loadRegistration(); err == nil {
+		user.registration = reg
+	}
+
+	return user, nil
+}
+
+func (m *Manager) loadOrGenerateAccountKey() (crypto.Signer, error) {
+	if m.cfg.CacheDir != "" {
+		keyPEM, err := os.ReadFile(filepath.Join(m.cfg.CacheDir, accountKeyFileName))
+		if err == nil {
+			key, err := certcrypto.ParsePEMPrivateKey(keyPEM)
+			if err != nil {
+				return nil, fmt.Errorf("parse cached acme account key: %w", err)
+			}
+			signer, ok := key.(crypto.Signer)
+			if !ok {
+				return nil, errors.New("cached acme account key does not support signing")
+			}
+			return signer, nil
+		}
+	}
+
+	key, err := certcrypto.GeneratePrivateKey(certcrypto.RSA2048)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.cfg.CacheDir != "" {
+		if err := os.MkdirAll(m.cfg.CacheDir, 0o700); err != nil {
+			log.Warnf("acme: failed to create cache dir %s: %s", m.cfg.CacheDir, err)
+		} else if err := os.WriteFile(filepath.Join(m.cfg.CacheDir, accountKeyFileName), certcrypto.PEMEncode(key), 0o600); err != nil {
+			log.Warnf("acme: failed to cache account key: %s", err)
+		}
+	}
+
+	return key, nil
+}
+
+func (m *Manager) loadRegistration() (*registration.Resource, error) {
+	if m.cfg.CacheDir == "" {
+		return nil, errors.New("acme: no cache dir configured")
+	}
+
+	data, err := os.ReadFile(filepath.Join(m.cfg.CacheDir, accountRegFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var reg registration.Resource
+	if err := json.Unmarshal(data, &reg); err != nil {
+		return nil, fmt.Errorf("parse cached acme account registration: %w", err)
+	}
+	return &reg, nil
+}
+
+func (m *Manager) saveRegistration(reg *registration.Resource) error {
+	if m.cfg.CacheDir == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(reg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.cfg.CacheDir, 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(m.cfg.CacheDir, accountRegFileName), data, 0o600)
+}
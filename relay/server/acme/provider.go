@@ -0,0 +1,56 @@
+package acme
+
+import (
+	"fmt"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/providers/dns/cloudflare"
+	"github.com/go-acme/lego/v4/providers/dns/gandiv5"
+	"github.com/go-acme/lego/v4/providers/dns/route53"
+)
+
+// NewDNSProvider builds a lego DNS-01 challenge.Provider from a DNS01ProviderConfig, so the
+// ACME manager can satisfy DNS-01 challenges without exposing port 80/443 to the CA.
+func NewDNSProvider(cfg DNS01ProviderConfig) (challenge.Provider, error) {
+	switch cfg.Name {
+	case "route53":
+		return newRoute53Provider(cfg.Credentials)
+	case "cloudflare":
+		return newCloudflareProvider(cfg.Credentials)
+	case "gandi":
+		return newGandiProvider(cfg.Credentials)
+	default:
+		return nil, fmt.Errorf("unsupported dns-01 provider %q", cfg.Name)
+	}
+}
+
+func newRoute53Provider(creds map[string]string) (challenge.Provider, error) {
+	cfg := route53.NewDefaultConfig()
+	if v := creds["access_key_id"]; v != "" {
+		cfg.AccessKeyID = v
+	}
+	if v := creds["secret_access_key"]; v != "" {
+		cfg.SecretAccessKey = v
+	}
+	if v := creds["hosted_zone_id"]; v != "" {
+		cfg.HostedZoneID = v
+	}
+	if v := creds["region"]; v != "" {
+		cfg.Region = v
+	}
+	return route53.NewDNSProviderConfig(cfg)
+}
+
+func newCloudflareProvider(creds map[string]string) (challenge.Provider, error) {
+	cfg := cloudflare.NewDefaultConfig()
+	cfg.AuthToken = creds["api_token"]
+	cfg.AuthEmail = creds["email"]
+	cfg.AuthKey = creds["api_key"]
+	return cloudflare.NewDNSProviderConfig(cfg)
+}
+
+func newGandiProvider(creds map[string]string) (challenge.Provider, error) {
+	cfg := gandiv5.NewDefaultConfig()
+	cfg.APIKey = creds["api_key"]
+	return gandiv5.NewDNSProviderConfig(cfg)
+}
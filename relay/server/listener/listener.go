@@ -0,0 +1,20 @@
+// Package listener defines the transport-agnostic surface the relay Server binds to. Each
+// transport (WS, UDP, QUIC, ...) implements Listener and adapts its accepted connections to
+// the same net.Conn shape, so Server.relay.Accept doesn't need to know which transport a
+// relayed peer arrived on.
+package listener
+
+import "net"
+
+// AcceptFunc is called by a Listener for every newly accepted relay connection
+type AcceptFunc func(conn net.Conn)
+
+// Listener binds a transport and delivers accepted connections to an AcceptFunc until Close
+// is called
+type Listener interface {
+	// Listen blocks, accepting connections and handing each to accept, until the listener
+	// is closed
+	Listen(accept AcceptFunc) error
+	// Close stops accepting new connections
+	Close() error
+}
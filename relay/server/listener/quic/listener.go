@@ -0,0 +1,85 @@
+// Package quic implements a relay listener.Listener over QUIC. Compared to the WS and UDP
+// listeners, QUIC gives the relay a single-RTT handshake, connection migration across NAT
+// rebinds (critical for mobile peers whose UDP 5-tuple changes on a Wi-Fi<->LTE handoff), and
+// multiplexed, reliable streams per relayed peer without head-of-line blocking.
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"sync"
+
+	"github.com/quic-go/quic-go"
+
+	"github.com/netbirdio/netbird/relay/server/listener"
+)
+
+// ALPN is the protocol negotiated on the QUIC handshake for the relay
+const ALPN = "nb-relay/1"
+
+// Listener accepts relayed peer connections over QUIC, reusing the relay's existing TLS
+// configuration and adapting each accepted stream to the net.Conn-shaped surface the other
+// listeners produce.
+type Listener struct {
+	Address   string
+	TLSConfig *tls.Config
+
+	mu       sync.Mutex
+	listener *quic.Listener
+	closed   bool
+}
+
+// Listen binds Address and, for every accepted QUIC connection, hands each stream the peer
+// opens on it to accept. It blocks until the listener is closed.
+func (l *Listener) Listen(accept listener.AcceptFunc) error {
+	tlsConf := l.TLSConfig.Clone()
+	tlsConf.NextProtos = []string{ALPN}
+
+	ln, err := quic.ListenAddr(l.Address, tlsConf, &quic.Config{})
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return ln.Close()
+	}
+	l.listener = ln
+	l.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept(context.Background())
+		if err != nil {
+			return err
+		}
+
+		go l.acceptStreams(conn, accept)
+	}
+}
+
+// acceptStreams hands every stream opened on conn to accept as its own net.Conn, so a single
+// QUIC connection can multiplex several relayed peer sessions without blocking on each other.
+func (l *Listener) acceptStreams(conn *quic.Conn, accept listener.AcceptFunc) {
+	for {
+		stream, err := conn.AcceptStream(context.Background())
+		if err != nil {
+			return
+		}
+
+		accept(newStreamConn(conn, stream))
+	}
+}
+
+// Close stops accepting new QUIC connections
+func (l *Listener) Close() error {
+	l.mu.Lock()
+	l.closed = true
+	ln := l.listener
+	l.mu.Unlock()
+
+	if ln == nil {
+		return nil
+	}
+	return ln.Close()
+}
@@ -0,0 +1,39 @@
+package quic
+
+import (
+	"net"
+	"time"
+
+	"github.com/quic-go/quic-go"
+)
+
+// streamConn adapts a quic.Stream, plus the quic.Conn it belongs to, to the net.Conn interface
+// so the relay can treat it the same way as a WS or UDP connection.
+type streamConn struct {
+	*quic.Stream
+	conn *quic.Conn
+}
+
+func newStreamConn(conn *quic.Conn, stream *quic.Stream) net.Conn {
+	return &streamConn{Stream: stream, conn: conn}
+}
+
+func (s *streamConn) LocalAddr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+func (s *streamConn) RemoteAddr() net.Addr {
+	return s.conn.RemoteAddr()
+}
+
+func (s *streamConn) SetDeadline(t time.Time) error {
+	if err := s.Stream.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return s.Stream.SetWriteDeadline(t)
+}
+
+func (s *streamConn) Close() error {
+	s.Stream.CancelRead(0)
+	return s.Stream.Close()
+}
@@ -9,7 +9,9 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/netbirdio/netbird/relay/server/acme"
 	"github.com/netbirdio/netbird/relay/server/listener"
+	"github.com/netbirdio/netbird/relay/server/listener/quic"
 	"github.com/netbirdio/netbird/relay/server/listener/udp"
 	"github.com/netbirdio/netbird/relay/server/listener/ws"
 )
@@ -17,12 +19,26 @@ import (
 type Config struct {
 	Address   string
 	TLSConfig *tls.Config
+	// ACME, when set, takes over TLSConfig: certificates are obtained and renewed
+	// automatically instead of being preconfigured by the operator.
+	ACME *acme.Config
+	// QUIC configures the optional QUIC listener, disabled by default so existing
+	// deployments are unaffected.
+	QUIC QUICConfig
+}
+
+// QUICConfig enables the relay's QUIC listener alongside WS and UDP
+type QUICConfig struct {
+	Enabled bool
 }
 
 type Server struct {
-	relay       *Relay
-	uDPListener listener.Listener
-	wSListener  listener.Listener
+	relay        *Relay
+	uDPListener  listener.Listener
+	wSListener   listener.Listener
+	quicListener listener.Listener
+
+	acmeCancel context.CancelFunc
 }
 
 func NewServer() *Server {
@@ -35,9 +51,22 @@ func (r *Server) Listen(cfg Config) error {
 	wg := sync.WaitGroup{}
 	wg.Add(2)
 
+	tlsConfig := cfg.TLSConfig
+	if cfg.ACME != nil {
+		acmeCtx, cancel := context.WithCancel(context.Background())
+		r.acmeCancel = cancel
+
+		acmeMgr, err := acme.NewManager(acmeCtx, *cfg.ACME)
+		if err != nil {
+			cancel()
+			return err
+		}
+		tlsConfig = acmeMgr.TLSConfig()
+	}
+
 	r.wSListener = &ws.Listener{
 		Address:   cfg.Address,
-		TLSConfig: cfg.TLSConfig,
+		TLSConfig: tlsConfig,
 	}
 
 	var wslErr error
@@ -59,11 +88,38 @@ func (r *Server) Listen(cfg Config) error {
 		}
 	}()
 
-	err := errors.Join(wslErr, udpLErr)
-	return err
+	var quicLErr error
+	if cfg.QUIC.Enabled {
+		wg.Add(1)
+
+		r.quicListener = &quic.Listener{
+			Address:   cfg.Address,
+			TLSConfig: tlsConfig,
+		}
+
+		go func() {
+			defer wg.Done()
+			quicLErr = r.quicListener.Listen(r.relay.Accept)
+			if quicLErr != nil {
+				log.Errorf("failed to bind quic server: %s", quicLErr)
+			}
+		}()
+	}
+
+	// Each Listener's Listen blocks until Close is called, so Listen only returns once every
+	// listener has stopped; wg.Wait() is also what makes reading wslErr/udpLErr/quicLErr below
+	// race-free, since sync.WaitGroup establishes a happens-before edge between each Done() and
+	// this Wait() returning.
+	wg.Wait()
+
+	return errors.Join(wslErr, udpLErr, quicLErr)
 }
 
 func (r *Server) Close() error {
+	if r.acmeCancel != nil {
+		r.acmeCancel()
+	}
+
 	var wErr error
 	// stop service new connections
 	if r.wSListener != nil {
@@ -75,11 +131,16 @@ func (r *Server) Close() error {
 		uErr = r.uDPListener.Close()
 	}
 
+	var qErr error
+	if r.quicListener != nil {
+		qErr = r.quicListener.Close()
+	}
+
 	// close accepted connections gracefully
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 	r.relay.Close(ctx)
 
-	err := errors.Join(wErr, uErr)
+	err := errors.Join(wErr, uErr, qErr)
 	return err
 }